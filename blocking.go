@@ -0,0 +1,375 @@
+package laney
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by the Ctx/Timeout methods of the Blocking*
+// container variants once the container has been Close()d: any callers
+// still blocked wake up with it, every push attempted afterwards is
+// rejected with it, and pops return it once the remaining items have
+// been drained.
+var ErrClosed = errors.New("laney: container closed")
+
+// waitCtx blocks on cond.Wait, but also wakes up and returns ctx.Err() if
+// ctx is canceled first. cond.L must already be held by the caller, as
+// required by sync.Cond.
+func waitCtx(ctx context.Context, cond *sync.Cond) error {
+	if ctx.Done() != nil {
+		stop := context.AfterFunc(ctx, func() {
+			cond.L.Lock()
+			cond.Broadcast()
+			cond.L.Unlock()
+		})
+		defer stop()
+	}
+
+	cond.Wait()
+
+	return ctx.Err()
+}
+
+// BlockingDeque wraps a Deque with PushCtx/PopCtx methods that block
+// while the deque is full/empty instead of failing or returning the zero
+// value, waking exactly one waiter per state transition. It is built for
+// worker-pool style producer/consumer code that wants graceful shutdown
+// via Close rather than rolling its own coordination on top of the
+// non-blocking Deque.
+type BlockingDeque[T any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	deque    *Deque[T]
+	closed   bool
+}
+
+// NewBlockingDeque creates a BlockingDeque.
+func NewBlockingDeque[T any]() *BlockingDeque[T] {
+	return NewCappedBlockingDeque[T](-1)
+}
+
+// NewCappedBlockingDeque creates a BlockingDeque with the specified
+// capacity limit; PushCtx blocks while the deque is at capacity.
+func NewCappedBlockingDeque[T any](capacity int) *BlockingDeque[T] {
+	bd := &BlockingDeque[T]{
+		deque: NewCappedDeque[T](capacity),
+	}
+	bd.notEmpty = sync.NewCond(&bd.mu)
+	bd.notFull = sync.NewCond(&bd.mu)
+	return bd
+}
+
+// PushCtx appends item to the back of the deque, blocking while the deque
+// is full until room frees up, ctx is canceled, or the deque is closed.
+func (bd *BlockingDeque[T]) PushCtx(ctx context.Context, item T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	bd.mu.Lock()
+	defer bd.mu.Unlock()
+
+	for !bd.closed && bd.deque.Full() {
+		if err := waitCtx(ctx, bd.notFull); err != nil {
+			return err
+		}
+	}
+
+	if bd.closed {
+		return ErrClosed
+	}
+
+	bd.deque.Append(item)
+	bd.notEmpty.Signal()
+
+	return nil
+}
+
+// PopCtx removes and returns the front item of the deque, blocking while
+// it is empty until an item arrives, ctx is canceled, or the deque is
+// closed and drained.
+func (bd *BlockingDeque[T]) PopCtx(ctx context.Context) (T, error) {
+	bd.mu.Lock()
+	defer bd.mu.Unlock()
+
+	for !bd.closed && bd.deque.Empty() {
+		if err := waitCtx(ctx, bd.notEmpty); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	if bd.deque.Empty() {
+		var zero T
+		return zero, ErrClosed
+	}
+
+	item := bd.deque.Shift()
+	bd.notFull.Signal()
+
+	return item, nil
+}
+
+// PopTimeout is PopCtx with a relative deadline, returning false if it
+// expires before an item is available.
+func (bd *BlockingDeque[T]) PopTimeout(d time.Duration) (T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	item, err := bd.PopCtx(ctx)
+	return item, err == nil
+}
+
+// Close wakes every blocked PushCtx/PopCtx caller with ErrClosed and
+// causes all future pushes to be rejected the same way. Pops continue to
+// drain whatever is already in the deque.
+func (bd *BlockingDeque[T]) Close() {
+	bd.mu.Lock()
+	defer bd.mu.Unlock()
+
+	bd.closed = true
+	bd.notEmpty.Broadcast()
+	bd.notFull.Broadcast()
+}
+
+// Size returns the actual deque size
+func (bd *BlockingDeque[T]) Size() int {
+	return bd.deque.Size()
+}
+
+// Empty checks if the deque is empty
+func (bd *BlockingDeque[T]) Empty() bool {
+	return bd.deque.Empty()
+}
+
+// Full checks if the deque is full
+func (bd *BlockingDeque[T]) Full() bool {
+	return bd.deque.Full()
+}
+
+// BlockingQueue wraps a Queue with PushCtx/PopCtx methods that block
+// while the queue is full/empty instead of failing or returning the zero
+// value. See BlockingDeque for the blocking and shutdown semantics.
+type BlockingQueue[T any] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    *Queue[T]
+	closed   bool
+}
+
+// NewBlockingQueue creates a BlockingQueue.
+func NewBlockingQueue[T any]() *BlockingQueue[T] {
+	return NewCappedBlockingQueue[T](-1)
+}
+
+// NewCappedBlockingQueue creates a BlockingQueue with the specified
+// capacity limit; PushCtx blocks while the queue is at capacity.
+func NewCappedBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	bq := &BlockingQueue[T]{
+		queue: NewCappedQueue[T](capacity),
+	}
+	bq.notEmpty = sync.NewCond(&bq.mu)
+	bq.notFull = sync.NewCond(&bq.mu)
+	return bq
+}
+
+// PushCtx enqueues item at the back of the queue, blocking while the
+// queue is full until room frees up, ctx is canceled, or the queue is
+// closed.
+func (bq *BlockingQueue[T]) PushCtx(ctx context.Context, item T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	for !bq.closed && bq.queue.Full() {
+		if err := waitCtx(ctx, bq.notFull); err != nil {
+			return err
+		}
+	}
+
+	if bq.closed {
+		return ErrClosed
+	}
+
+	bq.queue.Enqueue(item)
+	bq.notEmpty.Signal()
+
+	return nil
+}
+
+// PopCtx dequeues the front item of the queue, blocking while it is empty
+// until an item arrives, ctx is canceled, or the queue is closed and
+// drained.
+func (bq *BlockingQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	for !bq.closed && bq.queue.Empty() {
+		if err := waitCtx(ctx, bq.notEmpty); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+
+	if bq.queue.Empty() {
+		var zero T
+		return zero, ErrClosed
+	}
+
+	item := bq.queue.Dequeue()
+	bq.notFull.Signal()
+
+	return item, nil
+}
+
+// PopTimeout is PopCtx with a relative deadline, returning false if it
+// expires before an item is available.
+func (bq *BlockingQueue[T]) PopTimeout(d time.Duration) (T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	item, err := bq.PopCtx(ctx)
+	return item, err == nil
+}
+
+// Close wakes every blocked PushCtx/PopCtx caller with ErrClosed and
+// causes all future pushes to be rejected the same way. Pops continue to
+// drain whatever is already in the queue.
+func (bq *BlockingQueue[T]) Close() {
+	bq.mu.Lock()
+	defer bq.mu.Unlock()
+
+	bq.closed = true
+	bq.notEmpty.Broadcast()
+	bq.notFull.Broadcast()
+}
+
+// Size returns the actual queue size
+func (bq *BlockingQueue[T]) Size() int {
+	return bq.queue.Size()
+}
+
+// Empty checks if the queue is empty
+func (bq *BlockingQueue[T]) Empty() bool {
+	return bq.queue.Empty()
+}
+
+// Full checks if the queue is full
+func (bq *BlockingQueue[T]) Full() bool {
+	return bq.queue.Full()
+}
+
+// BlockingPQueue wraps a PQueue with a PopCtx method that blocks while
+// the queue is empty instead of returning the zero value. PQueue has no
+// capacity limit, so PushCtx never blocks on room; it only checks ctx and
+// Close. See BlockingDeque for the shutdown semantics.
+type BlockingPQueue[V any, P cmp.Ordered] struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	pqueue   *PQueue[V, P]
+	closed   bool
+}
+
+// NewBlockingMaxPQueue creates a BlockingPQueue that pops the highest
+// priority item first.
+func NewBlockingMaxPQueue[V any, P cmp.Ordered]() *BlockingPQueue[V, P] {
+	return newBlockingPQueue[V, P](NewMaxPQueue[V, P]())
+}
+
+// NewBlockingMinPQueue creates a BlockingPQueue that pops the lowest
+// priority item first.
+func NewBlockingMinPQueue[V any, P cmp.Ordered]() *BlockingPQueue[V, P] {
+	return newBlockingPQueue[V, P](NewMinPQueue[V, P]())
+}
+
+func newBlockingPQueue[V any, P cmp.Ordered](pq *PQueue[V, P]) *BlockingPQueue[V, P] {
+	bpq := &BlockingPQueue[V, P]{
+		pqueue: pq,
+	}
+	bpq.notEmpty = sync.NewCond(&bpq.mu)
+	return bpq
+}
+
+// PushCtx pushes value into the queue with the given priority, failing
+// only if ctx is already canceled or the queue is closed.
+func (bpq *BlockingPQueue[V, P]) PushCtx(ctx context.Context, value V, priority P) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	bpq.mu.Lock()
+	defer bpq.mu.Unlock()
+
+	if bpq.closed {
+		return ErrClosed
+	}
+
+	bpq.pqueue.Push(value, priority)
+	bpq.notEmpty.Signal()
+
+	return nil
+}
+
+// PopCtx removes and returns the head item of the queue, blocking while
+// it is empty until an item arrives, ctx is canceled, or the queue is
+// closed and drained.
+func (bpq *BlockingPQueue[V, P]) PopCtx(ctx context.Context) (V, P, error) {
+	bpq.mu.Lock()
+	defer bpq.mu.Unlock()
+
+	for !bpq.closed && bpq.pqueue.Empty() {
+		if err := waitCtx(ctx, bpq.notEmpty); err != nil {
+			var zeroV V
+			var zeroP P
+			return zeroV, zeroP, err
+		}
+	}
+
+	if bpq.pqueue.Empty() {
+		var zeroV V
+		var zeroP P
+		return zeroV, zeroP, ErrClosed
+	}
+
+	value, priority, _ := bpq.pqueue.Pop()
+	return value, priority, nil
+}
+
+// PopTimeout is PopCtx with a relative deadline, returning false if it
+// expires before an item is available.
+func (bpq *BlockingPQueue[V, P]) PopTimeout(d time.Duration) (V, P, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	value, priority, err := bpq.PopCtx(ctx)
+	return value, priority, err == nil
+}
+
+// Close wakes every blocked PopCtx caller with ErrClosed and causes all
+// future pushes to be rejected the same way. Pops continue to drain
+// whatever is already in the queue.
+func (bpq *BlockingPQueue[V, P]) Close() {
+	bpq.mu.Lock()
+	defer bpq.mu.Unlock()
+
+	bpq.closed = true
+	bpq.notEmpty.Broadcast()
+}
+
+// Size returns the elements present in the priority queue count
+func (bpq *BlockingPQueue[V, P]) Size() int {
+	return bpq.pqueue.Size()
+}
+
+// Empty checks if the priority queue is empty
+func (bpq *BlockingPQueue[V, P]) Empty() bool {
+	return bpq.pqueue.Empty()
+}
@@ -0,0 +1,428 @@
+package laney
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBlockingQueuePopCtxWaitsForPush(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	result := make(chan int, 1)
+	go func() {
+		v, err := bq.PopCtx(context.Background())
+		if err != nil {
+			t.Errorf("PopCtx() error = %v, want nil", err)
+		}
+		result <- v
+	}()
+
+	time.Sleep(20 * time.Millisecond) // give the popper a chance to block
+	if err := bq.PushCtx(context.Background(), 42); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+
+	select {
+	case v := <-result:
+		if v != 42 {
+			t.Fatalf("PopCtx() = %d, want 42", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after a matching PushCtx")
+	}
+}
+
+func TestBlockingQueuePopCtxCancel(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := bq.PopCtx(ctx)
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("PopCtx() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after ctx cancellation")
+	}
+}
+
+func TestBlockingQueuePopTimeout(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	if _, ok := bq.PopTimeout(20 * time.Millisecond); ok {
+		t.Fatalf("PopTimeout() on an empty queue returned ok=true")
+	}
+}
+
+func TestBlockingQueueClosePendingPop(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := bq.PopCtx(context.Background())
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	bq.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("PopCtx() error = %v, want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after Close()")
+	}
+
+	if err := bq.PushCtx(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("PushCtx() after Close() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestBlockingQueueCloseDrainsExisting(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	if err := bq.PushCtx(context.Background(), 1); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+	bq.Close()
+
+	v, err := bq.PopCtx(context.Background())
+	if err != nil {
+		t.Fatalf("PopCtx() error = %v, want nil (item pushed before Close should still drain)", err)
+	}
+	if v != 1 {
+		t.Fatalf("PopCtx() = %d, want 1", v)
+	}
+
+	if _, err := bq.PopCtx(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("PopCtx() on a drained, closed queue error = %v, want ErrClosed", err)
+	}
+}
+
+func TestBlockingQueueCappedPushBlocksUntilRoom(t *testing.T) {
+	bq := NewCappedBlockingQueue[int](1)
+
+	if err := bq.PushCtx(context.Background(), 1); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- bq.PushCtx(context.Background(), 2)
+	}()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("PushCtx() on a full capped queue returned early with err=%v, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := bq.PopCtx(context.Background()); err != nil {
+		t.Fatalf("PopCtx() error = %v, want nil", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("PushCtx() error = %v, want nil once room freed up", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PushCtx() did not unblock once room freed up")
+	}
+}
+
+func TestBlockingQueuePushCtxAlreadyCanceled(t *testing.T) {
+	bq := NewBlockingQueue[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := bq.PushCtx(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PushCtx() with an already-canceled ctx error = %v, want context.Canceled", err)
+	}
+	if got := bq.Size(); got != 0 {
+		t.Fatalf("Size() = %d, want 0 (canceled PushCtx must not enqueue)", got)
+	}
+}
+
+func TestBlockingDequePopCtxWaitsForPush(t *testing.T) {
+	bd := NewBlockingDeque[int]()
+
+	result := make(chan int, 1)
+	go func() {
+		v, err := bd.PopCtx(context.Background())
+		if err != nil {
+			t.Errorf("PopCtx() error = %v, want nil", err)
+		}
+		result <- v
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := bd.PushCtx(context.Background(), 42); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+
+	select {
+	case v := <-result:
+		if v != 42 {
+			t.Fatalf("PopCtx() = %d, want 42", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after a matching PushCtx")
+	}
+}
+
+func TestBlockingDequePushCtxAlreadyCanceled(t *testing.T) {
+	bd := NewBlockingDeque[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := bd.PushCtx(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PushCtx() with an already-canceled ctx error = %v, want context.Canceled", err)
+	}
+	if got := bd.Size(); got != 0 {
+		t.Fatalf("Size() = %d, want 0 (canceled PushCtx must not enqueue)", got)
+	}
+}
+
+func TestBlockingDequePopCtxCancel(t *testing.T) {
+	bd := NewBlockingDeque[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := bd.PopCtx(ctx)
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("PopCtx() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after ctx cancellation")
+	}
+}
+
+func TestBlockingDequeClosePendingPop(t *testing.T) {
+	bd := NewBlockingDeque[int]()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := bd.PopCtx(context.Background())
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	bd.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("PopCtx() error = %v, want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after Close()")
+	}
+
+	if err := bd.PushCtx(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("PushCtx() after Close() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestBlockingDequeCappedPushBlocksUntilRoom(t *testing.T) {
+	bd := NewCappedBlockingDeque[int](1)
+
+	if err := bd.PushCtx(context.Background(), 1); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- bd.PushCtx(context.Background(), 2)
+	}()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("PushCtx() on a full capped deque returned early with err=%v, want it to block", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := bd.PopCtx(context.Background()); err != nil {
+		t.Fatalf("PopCtx() error = %v, want nil", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("PushCtx() error = %v, want nil once room freed up", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PushCtx() did not unblock once room freed up")
+	}
+}
+
+func TestBlockingPQueuePopCtxWaitsForPush(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[string, int]()
+
+	type result struct {
+		value    string
+		priority int
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		v, p, err := bpq.PopCtx(context.Background())
+		if err != nil {
+			t.Errorf("PopCtx() error = %v, want nil", err)
+		}
+		resultCh <- result{v, p}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := bpq.PushCtx(context.Background(), "a", 1); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+
+	select {
+	case r := <-resultCh:
+		if r.value != "a" || r.priority != 1 {
+			t.Fatalf("PopCtx() = (%q, %d), want (\"a\", 1)", r.value, r.priority)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after a matching PushCtx")
+	}
+}
+
+// TestBlockingPQueuePushNeverBlocksOnCapacity exercises the capacity-less
+// PushCtx semantics called out in the request: PQueue has no capacity
+// limit, so pushing many items back to back must never block waiting for
+// room, even though the sibling Deque/Queue wrappers do.
+func TestBlockingPQueuePushNeverBlocksOnCapacity(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[int, int]()
+
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			if err := bpq.PushCtx(context.Background(), i, i); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("PushCtx() error = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PushCtx() blocked, but BlockingPQueue has no capacity to block on")
+	}
+
+	if got := bpq.Size(); got != 1000 {
+		t.Fatalf("Size() = %d, want 1000", got)
+	}
+}
+
+func TestBlockingPQueuePushCtxAlreadyCanceled(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[string, int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := bpq.PushCtx(ctx, "a", 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PushCtx() with an already-canceled ctx error = %v, want context.Canceled", err)
+	}
+	if got := bpq.Size(); got != 0 {
+		t.Fatalf("Size() = %d, want 0 (canceled PushCtx must not enqueue)", got)
+	}
+}
+
+func TestBlockingPQueuePopCtxCancel(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[string, int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := bpq.PopCtx(ctx)
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("PopCtx() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after ctx cancellation")
+	}
+}
+
+func TestBlockingPQueueClosePendingPop(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[string, int]()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, _, err := bpq.PopCtx(context.Background())
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	bpq.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("PopCtx() error = %v, want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PopCtx() did not unblock after Close()")
+	}
+
+	if err := bpq.PushCtx(context.Background(), "a", 1); !errors.Is(err, ErrClosed) {
+		t.Fatalf("PushCtx() after Close() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestBlockingPQueueCloseDrainsExisting(t *testing.T) {
+	bpq := NewBlockingMaxPQueue[string, int]()
+
+	if err := bpq.PushCtx(context.Background(), "a", 1); err != nil {
+		t.Fatalf("PushCtx() error = %v, want nil", err)
+	}
+	bpq.Close()
+
+	value, priority, err := bpq.PopCtx(context.Background())
+	if err != nil {
+		t.Fatalf("PopCtx() error = %v, want nil (item pushed before Close should still drain)", err)
+	}
+	if value != "a" || priority != 1 {
+		t.Fatalf("PopCtx() = (%q, %d), want (\"a\", 1)", value, priority)
+	}
+
+	if _, _, err := bpq.PopCtx(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("PopCtx() on a drained, closed queue error = %v, want ErrClosed", err)
+	}
+}
@@ -1,20 +1,30 @@
 package laney
 
 import (
-	"container/list"
 	"sync"
 )
 
-// Deque is a head-tail linked list data structure implementation.
-// It is based on a doubly linked list container, so that every
-// operations time complexity is O(1).
+// defaultMinCapacityExp is the default minimum size, expressed as a power
+// of two exponent, that the Deque's backing buffer is allowed to shrink to.
+// A value of 3 keeps at least 8 slots allocated once the buffer has grown,
+// avoiding churn for deques that oscillate around a small size.
+const defaultMinCapacityExp uint = 3
+
+// Deque is a head-tail ring buffer data structure implementation.
+// It is based on a growable circular buffer, so that pushes and pops
+// at either end and indexed access run in amortized O(1) time and
+// elements enjoy good cache locality compared to a linked list.
+// Rotate, Snapshot and Clone can take time proportional to Size().
 //
 // every operations over an instiated Deque are synchronized and
 // safe for concurrent usage.
 type Deque[T any] struct {
 	sync.RWMutex
-	container *list.List
-	capacity  int
+	buf      []T
+	head     int
+	count    int
+	capacity int
+	minExp   uint
 }
 
 // NewDeque creates a Deque.
@@ -25,8 +35,23 @@ func NewDeque[T any]() *Deque[T] {
 // NewCappedDeque creates a Deque with the specified capacity limit.
 func NewCappedDeque[T any](capacity int) *Deque[T] {
 	return &Deque[T]{
-		container: list.New(),
-		capacity:  capacity,
+		capacity: capacity,
+		minExp:   defaultMinCapacityExp,
+	}
+}
+
+// SetMinCapacity sets the minimum size, expressed as a power of two
+// exponent, that the Deque's backing buffer will not shrink below. Callers
+// doing bursty work can use this to avoid repeated grow/shrink cycles.
+func (s *Deque[T]) SetMinCapacity(exp uint) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.minExp = exp
+
+	minSize := s.minBufSize()
+	if len(s.buf) < minSize {
+		s.resizeTo(minSize)
 	}
 }
 
@@ -36,12 +61,15 @@ func (s *Deque[T]) Append(item T) bool {
 	s.Lock()
 	defer s.Unlock()
 
-	if s.capacity < 0 || s.container.Len() < s.capacity {
-		s.container.PushBack(item)
-		return true
+	if s.capacity >= 0 && s.count >= s.capacity {
+		return false
 	}
 
-	return false
+	s.growIfFull()
+	s.buf[s.physicalIndex(s.count)] = item
+	s.count++
+
+	return true
 }
 
 // Prepend inserts element at the Deques front in a O(1) time complexity,
@@ -50,12 +78,16 @@ func (s *Deque[T]) Prepend(item T) bool {
 	s.Lock()
 	defer s.Unlock()
 
-	if s.capacity < 0 || s.container.Len() < s.capacity {
-		s.container.PushFront(item)
-		return true
+	if s.capacity >= 0 && s.count >= s.capacity {
+		return false
 	}
 
-	return false
+	s.growIfFull()
+	s.head = s.wrap(s.head - 1)
+	s.buf[s.head] = item
+	s.count++
+
+	return true
 }
 
 // Pop removes the last element of the deque in a O(1) time complexity
@@ -64,13 +96,17 @@ func (s *Deque[T]) Pop() T {
 	defer s.Unlock()
 
 	var item T
-	var lastContainerItem *list.Element = nil
-
-	lastContainerItem = s.container.Back()
-	if lastContainerItem != nil {
-		item = s.container.Remove(lastContainerItem).(T)
+	if s.count == 0 {
+		return item
 	}
 
+	idx := s.physicalIndex(s.count - 1)
+	item = s.buf[idx]
+	var zero T
+	s.buf[idx] = zero
+	s.count--
+	s.shrinkIfSparse()
+
 	return item
 }
 
@@ -80,13 +116,17 @@ func (s *Deque[T]) Shift() T {
 	defer s.Unlock()
 
 	var item T
-	var firstContainerItem *list.Element = nil
-
-	firstContainerItem = s.container.Front()
-	if firstContainerItem != nil {
-		item = s.container.Remove(firstContainerItem).(T)
+	if s.count == 0 {
+		return item
 	}
 
+	item = s.buf[s.head]
+	var zero T
+	s.buf[s.head] = zero
+	s.head = s.wrap(s.head + 1)
+	s.count--
+	s.shrinkIfSparse()
+
 	return item
 }
 
@@ -95,13 +135,12 @@ func (s *Deque[T]) First() T {
 	s.RLock()
 	defer s.RUnlock()
 
-	item := s.container.Front()
-	if item != nil {
-		return item.Value.(T)
-	} else {
+	if s.count == 0 {
 		var nothing T
 		return nothing
 	}
+
+	return s.buf[s.head]
 }
 
 // Last returns the last value stored in the deque in a O(1) time complexity
@@ -109,13 +148,83 @@ func (s *Deque[T]) Last() T {
 	s.RLock()
 	defer s.RUnlock()
 
-	item := s.container.Back()
-	if item != nil {
-		return item.Value.(T)
-	} else {
+	if s.count == 0 {
 		var nothing T
 		return nothing
 	}
+
+	return s.buf[s.physicalIndex(s.count-1)]
+}
+
+// At returns the item stored at logical index i in a O(1) time complexity,
+// or the zero value if i is out of range.
+func (s *Deque[T]) At(i int) T {
+	s.RLock()
+	defer s.RUnlock()
+
+	if i < 0 || i >= s.count {
+		var nothing T
+		return nothing
+	}
+
+	return s.buf[s.physicalIndex(i)]
+}
+
+// Set overwrites the item stored at logical index i in a O(1) time
+// complexity. It is a no-op if i is out of range.
+func (s *Deque[T]) Set(i int, v T) {
+	s.Lock()
+	defer s.Unlock()
+
+	if i < 0 || i >= s.count {
+		return
+	}
+
+	s.buf[s.physicalIndex(i)] = v
+}
+
+// Rotate shifts the deque's logical start by n positions in a single O(1)
+// operation whenever the backing buffer is full, since rotating then only
+// requires moving the head pointer rather than the data itself. When the
+// buffer isn't full the rotation falls back to moving at most
+// min(n, Size()-n) elements. A positive n moves the first n elements to the
+// back of the deque; a negative n rotates the other way.
+func (s *Deque[T]) Rotate(n int) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.count == 0 {
+		return
+	}
+
+	n = ((n % s.count) + s.count) % s.count
+	if n == 0 {
+		return
+	}
+
+	if s.count == len(s.buf) {
+		s.head = s.wrap(s.head + n)
+		return
+	}
+
+	if n <= s.count-n {
+		for i := 0; i < n; i++ {
+			item := s.buf[s.head]
+			var zero T
+			s.buf[s.head] = zero
+			s.head = s.wrap(s.head + 1)
+			s.buf[s.physicalIndex(s.count-1)] = item
+		}
+	} else {
+		for i := 0; i < s.count-n; i++ {
+			idx := s.physicalIndex(s.count - 1)
+			item := s.buf[idx]
+			var zero T
+			s.buf[idx] = zero
+			s.head = s.wrap(s.head - 1)
+			s.buf[s.head] = item
+		}
+	}
 }
 
 // Size returns the actual deque size
@@ -123,7 +232,7 @@ func (s *Deque[T]) Size() int {
 	s.RLock()
 	defer s.RUnlock()
 
-	return s.container.Len()
+	return s.count
 }
 
 // Capacity returns the capacity of the deque, or -1 if unlimited
@@ -138,7 +247,7 @@ func (s *Deque[T]) Empty() bool {
 	s.RLock()
 	defer s.RUnlock()
 
-	return s.container.Len() == 0
+	return s.count == 0
 }
 
 // Full checks if the deque is full
@@ -146,5 +255,147 @@ func (s *Deque[T]) Full() bool {
 	s.RLock()
 	defer s.RUnlock()
 
-	return s.capacity >= 0 && s.container.Len() >= s.capacity
+	return s.capacity >= 0 && s.count >= s.capacity
+}
+
+// Snapshot returns a consistent copy of the deque's contents, front to
+// back, taken under the read lock. Unlike draining the deque with Pop or
+// Shift, the deque itself is left untouched.
+func (s *Deque[T]) Snapshot() []T {
+	s.RLock()
+	defer s.RUnlock()
+
+	out := make([]T, s.count)
+	for i := 0; i < s.count; i++ {
+		out[i] = s.buf[s.physicalIndex(i)]
+	}
+	return out
+}
+
+// Clear empties the deque, releasing its backing buffer.
+func (s *Deque[T]) Clear() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.buf = nil
+	s.head = 0
+	s.count = 0
+}
+
+// Clone returns a new Deque with an independent copy of the same contents,
+// capacity and minimum capacity.
+func (s *Deque[T]) Clone() *Deque[T] {
+	s.RLock()
+	defer s.RUnlock()
+
+	clone := &Deque[T]{
+		capacity: s.capacity,
+		minExp:   s.minExp,
+	}
+
+	if s.count > 0 {
+		clone.buf = make([]T, len(s.buf))
+		for i := 0; i < s.count; i++ {
+			clone.buf[i] = s.buf[s.physicalIndex(i)]
+		}
+		clone.count = s.count
+	}
+
+	return clone
+}
+
+// physicalIndex converts a logical index (0 being the front) into an index
+// into buf, assuming the caller already holds the lock.
+func (s *Deque[T]) physicalIndex(i int) int {
+	return s.wrap(s.head + i)
+}
+
+// wrap normalizes i into the range [0, len(buf)), assuming len(buf) > 0.
+func (s *Deque[T]) wrap(i int) int {
+	n := len(s.buf)
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+// minBufSize returns the smallest buffer size allowed given minExp and, if
+// the deque is capped, the capacity itself.
+func (s *Deque[T]) minBufSize() int {
+	size := 1 << s.minExp
+	if s.capacity >= 0 && size > nextPow2(s.capacity) {
+		size = nextPow2(s.capacity)
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// growIfFull ensures buf has room for one more element, assuming the caller
+// already holds the lock.
+func (s *Deque[T]) growIfFull() {
+	if len(s.buf) == 0 {
+		s.resizeTo(s.minBufSize())
+		return
+	}
+
+	if s.count < len(s.buf) {
+		return
+	}
+
+	newSize := len(s.buf) * 2
+	if s.capacity >= 0 {
+		maxSize := nextPow2(s.capacity)
+		if newSize > maxSize {
+			newSize = maxSize
+		}
+	}
+	s.resizeTo(newSize)
+}
+
+// shrinkIfSparse halves the backing buffer when occupancy drops to a
+// quarter of its size, bottoming out at minBufSize, assuming the caller
+// already holds the lock.
+func (s *Deque[T]) shrinkIfSparse() {
+	minSize := s.minBufSize()
+	if len(s.buf) <= minSize {
+		return
+	}
+
+	if s.count > len(s.buf)/4 {
+		return
+	}
+
+	newSize := len(s.buf) / 2
+	if newSize < minSize {
+		newSize = minSize
+	}
+	s.resizeTo(newSize)
+}
+
+// resizeTo reallocates buf to newSize, preserving logical order starting at
+// index 0, assuming the caller already holds the lock.
+func (s *Deque[T]) resizeTo(newSize int) {
+	newBuf := make([]T, newSize)
+	for i := 0; i < s.count; i++ {
+		newBuf[i] = s.buf[s.physicalIndex(i)]
+	}
+	s.buf = newBuf
+	s.head = 0
+}
+
+// nextPow2 returns the smallest power of two greater than or equal to n,
+// with a floor of 1.
+func nextPow2(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
 }
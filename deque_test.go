@@ -0,0 +1,185 @@
+package laney
+
+import "testing"
+
+func TestDequeAppendPrependOrder(t *testing.T) {
+	d := NewDeque[int]()
+
+	d.Append(1)
+	d.Append(2)
+	d.Prepend(0)
+
+	if got := d.Size(); got != 3 {
+		t.Fatalf("Size() = %d, want 3", got)
+	}
+	for i, want := range []int{0, 1, 2} {
+		if got := d.At(i); got != want {
+			t.Fatalf("At(%d) = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestDequeGrowAcrossWrap(t *testing.T) {
+	d := NewDeque[int]()
+
+	// Push enough to force at least one grow, alternating ends so the
+	// backing buffer wraps around before it resizes.
+	for i := 0; i < 50; i++ {
+		if i%2 == 0 {
+			d.Append(i)
+		} else {
+			d.Prepend(i)
+		}
+	}
+
+	if got := d.Size(); got != 50 {
+		t.Fatalf("Size() = %d, want 50", got)
+	}
+
+	// Shifting everything back out should yield the prepended values in
+	// reverse (49, 47, ..., 1) followed by the appended ones in order
+	// (0, 2, ..., 48), proving the logical order survived the grow/copy.
+	want := make([]int, 0, 50)
+	for i := 49; i > 0; i -= 2 {
+		want = append(want, i)
+	}
+	for i := 0; i < 50; i += 2 {
+		want = append(want, i)
+	}
+	for i := 0; d.Size() > 0; i++ {
+		first := d.First()
+		got := d.Shift()
+		if got != first {
+			t.Fatalf("Shift() = %d, want %d (First())", got, first)
+		}
+		if got != want[i] {
+			t.Fatalf("Shift() #%d = %d, want %d", i, got, want[i])
+		}
+	}
+}
+
+func TestDequeShrinkAfterDrain(t *testing.T) {
+	d := NewDeque[int]()
+	d.SetMinCapacity(2) // minimum backing size of 4
+
+	for i := 0; i < 100; i++ {
+		d.Append(i)
+	}
+	for i := 0; i < 90; i++ {
+		d.Shift()
+	}
+
+	if got := d.Size(); got != 10 {
+		t.Fatalf("Size() = %d, want 10", got)
+	}
+	for i := 0; i < 10; i++ {
+		if got := d.At(i); got != 90+i {
+			t.Fatalf("At(%d) = %d, want %d", i, got, 90+i)
+		}
+	}
+}
+
+func TestDequeCappedRejectsOverCapacity(t *testing.T) {
+	d := NewCappedDeque[int](2)
+
+	if !d.Append(1) || !d.Append(2) {
+		t.Fatalf("expected first two Appends to succeed under capacity")
+	}
+	if d.Append(3) {
+		t.Fatalf("expected Append to fail once at capacity")
+	}
+	if !d.Full() {
+		t.Fatalf("expected Full() once at capacity")
+	}
+}
+
+func TestDequeSetOutOfRangeIsNoop(t *testing.T) {
+	d := NewDeque[int]()
+	d.Append(1)
+
+	d.Set(5, 42) // out of range, should not panic or grow the deque
+	d.Set(-1, 42)
+
+	if got := d.Size(); got != 1 {
+		t.Fatalf("Size() = %d, want 1 after out-of-range Set", got)
+	}
+	if got := d.At(0); got != 1 {
+		t.Fatalf("At(0) = %d, want 1", got)
+	}
+}
+
+func TestDequeRotate(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 5; i++ {
+		d.Append(i)
+	}
+
+	d.Rotate(2) // first two elements move to the back
+
+	want := []int{2, 3, 4, 0, 1}
+	for i, w := range want {
+		if got := d.At(i); got != w {
+			t.Fatalf("after Rotate(2), At(%d) = %d, want %d", i, got, w)
+		}
+	}
+
+	d.Rotate(-2) // rotate back to the original order
+
+	for i := 0; i < 5; i++ {
+		if got := d.At(i); got != i {
+			t.Fatalf("after Rotate(-2), At(%d) = %d, want %d", i, got, i)
+		}
+	}
+}
+
+func TestDequeRotateOnFullBuffer(t *testing.T) {
+	d := NewCappedDeque[int](4)
+	d.SetMinCapacity(2) // backing buffer size 4, matching capacity exactly
+
+	for i := 0; i < 4; i++ {
+		d.Append(i)
+	}
+
+	d.Rotate(1)
+
+	want := []int{1, 2, 3, 0}
+	for i, w := range want {
+		if got := d.At(i); got != w {
+			t.Fatalf("after Rotate(1) on a full buffer, At(%d) = %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestDequeCloneIsIndependent(t *testing.T) {
+	d := NewDeque[int]()
+	d.Append(1)
+	d.Append(2)
+
+	clone := d.Clone()
+	clone.Append(3)
+
+	if got := d.Size(); got != 2 {
+		t.Fatalf("original Size() = %d, want 2 (unaffected by clone mutation)", got)
+	}
+	if got := clone.Size(); got != 3 {
+		t.Fatalf("clone Size() = %d, want 3", got)
+	}
+}
+
+func TestDequeClear(t *testing.T) {
+	d := NewDeque[int]()
+	d.Append(1)
+	d.Append(2)
+
+	d.Clear()
+
+	if !d.Empty() {
+		t.Fatalf("expected deque to be empty after Clear()")
+	}
+	if !d.Append(3) {
+		t.Fatalf("expected deque to be usable after Clear()")
+	}
+	if got := d.First(); got != 3 {
+		t.Fatalf("First() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,291 @@
+package laney
+
+import (
+	"cmp"
+	"sync"
+	"time"
+)
+
+// defaultReheapifyInterval is the number of Push/Pop operations a
+// LazyPQueue performs before it re-evaluates every cached priority and
+// rebuilds its heap from scratch, bounding how stale Size() and the
+// overall shape of the heap are allowed to get between refreshes.
+const defaultReheapifyInterval = 256
+
+// LazyPQueue is a priority queue for values whose priority drifts over
+// time (token-bucket schedulers, TTL-based caches, flow-control balances,
+// ...) instead of being fixed at Push time.
+//
+// It keeps a primary binary heap ordered by each item's last known
+// priority, plus a secondary append-only slice of items pushed since the
+// primary was last touched. Rather than re-pushing every item whenever
+// priorities change, the user-supplied refresh function is invoked lazily,
+// only on the current head, at Pop/Peek time: if the refreshed priority no
+// longer belongs at the head, the item is sunk back into the heap and the
+// search continues until the true head is found. Every reheapifyEvery
+// operations the whole heap is rebuilt against freshly refreshed
+// priorities and the secondary buffer is folded in, so staleness never
+// grows unbounded even for items that are rarely at the head.
+//
+// It is synchronized and is safe for concurrent operations.
+type LazyPQueue[V any, P cmp.Ordered] struct {
+	sync.Mutex
+	primary        *PQueue[V, P]
+	secondary      []*item[V, P]
+	refresh        func(value V, now time.Time) (priority P, drop bool)
+	reheapifyEvery int
+	opsSinceReheap int
+}
+
+// NewMaxLazyPQueue creates a LazyPQueue that surfaces the item with the
+// highest refreshed priority first.
+func NewMaxLazyPQueue[V any, P cmp.Ordered](refresh func(V, time.Time) (P, bool)) *LazyPQueue[V, P] {
+	return NewLazyPQueueFunc[V, P](refresh, func(a, b P) bool {
+		return a < b
+	})
+}
+
+// NewMinLazyPQueue creates a LazyPQueue that surfaces the item with the
+// lowest refreshed priority first.
+func NewMinLazyPQueue[V any, P cmp.Ordered](refresh func(V, time.Time) (P, bool)) *LazyPQueue[V, P] {
+	return NewLazyPQueueFunc[V, P](refresh, func(a, b P) bool {
+		return a > b
+	})
+}
+
+// NewLazyPQueueFunc creates a LazyPQueue ordered by the provided less
+// function, with priorities kept fresh by refresh. See NewPQueueFunc for
+// the meaning of less.
+func NewLazyPQueueFunc[V any, P cmp.Ordered](refresh func(V, time.Time) (P, bool), less func(P, P) bool) *LazyPQueue[V, P] {
+	return &LazyPQueue[V, P]{
+		primary:        NewPQueueFunc[V, P](less),
+		refresh:        refresh,
+		reheapifyEvery: defaultReheapifyInterval,
+	}
+}
+
+// SetReheapifyInterval changes how many operations elapse between full
+// heap rebuilds. A value <= 0 disables the periodic rebuild entirely,
+// leaving staleness correction to the lazy Pop/Peek checks alone.
+func (pq *LazyPQueue[V, P]) SetReheapifyInterval(n int) {
+	pq.Lock()
+	defer pq.Unlock()
+
+	pq.reheapifyEvery = n
+}
+
+// Push adds value to the queue with its initial priority. The item lands
+// in the secondary buffer and is folded into the primary heap the next
+// time it is touched by Pop, Peek or a periodic reheapify.
+func (pq *LazyPQueue[V, P]) Push(value V, priority P) {
+	pq.Lock()
+	defer pq.Unlock()
+
+	pq.secondary = append(pq.secondary, newItem(value, priority))
+	pq.registerOpLocked(time.Time{})
+}
+
+// Pop removes and returns the item with the true head priority as of now,
+// refreshing (and, if necessary, re-sinking) candidate heads until it finds
+// one that is still on top. The returned bool is false if the queue was
+// empty once fully refreshed.
+func (pq *LazyPQueue[V, P]) Pop(now time.Time) (V, P, bool) {
+	pq.Lock()
+	defer pq.Unlock()
+
+	pq.registerOpLocked(now)
+	return pq.popLocked(now)
+}
+
+// Peek returns the item with the true head priority as of now without
+// removing it, performing the same lazy refresh as Pop.
+func (pq *LazyPQueue[V, P]) Peek(now time.Time) (V, P, bool) {
+	pq.Lock()
+	defer pq.Unlock()
+
+	pq.registerOpLocked(now)
+
+	for {
+		pq.mergeSecondaryLocked()
+
+		value, cached, ok := pq.primary.Head()
+		if !ok {
+			var zeroV V
+			var zeroP P
+			return zeroV, zeroP, false
+		}
+
+		refreshed, drop := pq.refresh(value, now)
+		if drop {
+			pq.primary.Pop()
+			continue
+		}
+
+		if refreshed == cached {
+			return value, cached, true
+		}
+
+		pq.primary.Pop()
+		pq.primary.Push(value, refreshed)
+	}
+}
+
+// MultiPop drains up to n items from the queue in head-first order as of
+// now, applying the same lazy refresh as Pop to each one. It returns fewer
+// than n pairs if the queue empties first.
+func (pq *LazyPQueue[V, P]) MultiPop(now time.Time, n int) ([]V, []P) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	pq.Lock()
+	defer pq.Unlock()
+
+	values := make([]V, 0, n)
+	priorities := make([]P, 0, n)
+
+	for i := 0; i < n; i++ {
+		pq.registerOpLocked(now)
+
+		value, priority, ok := pq.popLocked(now)
+		if !ok {
+			break
+		}
+
+		values = append(values, value)
+		priorities = append(priorities, priority)
+	}
+
+	return values, priorities
+}
+
+// Remove deletes every item (from either the primary heap or the
+// secondary buffer) for which matcher returns true, and returns how many
+// items were removed.
+func (pq *LazyPQueue[V, P]) Remove(matcher func(V) bool) int {
+	pq.Lock()
+	defer pq.Unlock()
+
+	removed := 0
+
+	keptSecondary := pq.secondary[:0]
+	for _, it := range pq.secondary {
+		if matcher(it.value) {
+			removed++
+			continue
+		}
+		keptSecondary = append(keptSecondary, it)
+	}
+	pq.secondary = keptSecondary
+
+	keptPrimary := make([]*item[V, P], 0, pq.primary.elemsCount)
+	for _, it := range pq.primary.items[1 : pq.primary.elemsCount+1] {
+		if matcher(it.value) {
+			removed++
+			continue
+		}
+		keptPrimary = append(keptPrimary, it)
+	}
+	pq.rebuildPrimaryLocked(keptPrimary)
+
+	return removed
+}
+
+// Size returns the total number of items held across the primary heap and
+// the secondary buffer.
+func (pq *LazyPQueue[V, P]) Size() int {
+	pq.Lock()
+	defer pq.Unlock()
+
+	return pq.primary.Size() + len(pq.secondary)
+}
+
+// Empty checks if the queue is empty
+func (pq *LazyPQueue[V, P]) Empty() bool {
+	pq.Lock()
+	defer pq.Unlock()
+
+	return pq.primary.Size() == 0 && len(pq.secondary) == 0
+}
+
+// popLocked implements Pop assuming the caller already holds the lock and
+// has already called registerOpLocked.
+func (pq *LazyPQueue[V, P]) popLocked(now time.Time) (V, P, bool) {
+	for {
+		pq.mergeSecondaryLocked()
+
+		value, cached, ok := pq.primary.Head()
+		if !ok {
+			var zeroV V
+			var zeroP P
+			return zeroV, zeroP, false
+		}
+
+		refreshed, drop := pq.refresh(value, now)
+		if drop {
+			pq.primary.Pop()
+			continue
+		}
+
+		if refreshed == cached {
+			return pq.primary.Pop()
+		}
+
+		pq.primary.Pop()
+		pq.primary.Push(value, refreshed)
+	}
+}
+
+// mergeSecondaryLocked folds every item waiting in the secondary buffer
+// into the primary heap, assuming the caller already holds the lock.
+func (pq *LazyPQueue[V, P]) mergeSecondaryLocked() {
+	for _, it := range pq.secondary {
+		pq.primary.Push(it.value, it.priority)
+	}
+	pq.secondary = pq.secondary[:0]
+}
+
+// registerOpLocked counts one operation towards reheapifyEvery and, once
+// the threshold is reached, rebuilds the primary heap against freshly
+// refreshed priorities, assuming the caller already holds the lock. A
+// zero now (as passed by Push) only counts the operation; it never
+// triggers a reheapify, since Push has no "now" of its own to refresh
+// against.
+func (pq *LazyPQueue[V, P]) registerOpLocked(now time.Time) {
+	pq.opsSinceReheap++
+
+	if pq.reheapifyEvery <= 0 || pq.opsSinceReheap < pq.reheapifyEvery {
+		return
+	}
+
+	if now.IsZero() {
+		return
+	}
+
+	pq.mergeSecondaryLocked()
+
+	kept := make([]*item[V, P], 0, pq.primary.elemsCount)
+	for _, it := range pq.primary.items[1 : pq.primary.elemsCount+1] {
+		refreshed, drop := pq.refresh(it.value, now)
+		if drop {
+			continue
+		}
+		it.priority = refreshed
+		kept = append(kept, it)
+	}
+	pq.rebuildPrimaryLocked(kept)
+
+	pq.opsSinceReheap = 0
+}
+
+// rebuildPrimaryLocked replaces the primary heap's contents with items and
+// restores the heap invariant, assuming the caller already holds the lock.
+func (pq *LazyPQueue[V, P]) rebuildPrimaryLocked(items []*item[V, P]) {
+	pq.primary.items = pq.primary.items[:1]
+	pq.primary.items = append(pq.primary.items, items...)
+	pq.primary.elemsCount = len(items)
+
+	for k := pq.primary.elemsCount / 2; k >= 1; k-- {
+		pq.primary.sink(k)
+	}
+}
@@ -0,0 +1,150 @@
+package laney
+
+import (
+	"testing"
+	"time"
+)
+
+// mapRefresh builds a refresh function for a LazyPQueue that reads each
+// item's current priority out of priorities, keyed by the item itself.
+// Mutating the map between calls simulates priority drift; "now" itself is
+// unused by these tests, which only care about the lazy vs. periodic
+// refresh mechanics.
+func mapRefresh(priorities map[string]int) func(string, time.Time) (int, bool) {
+	return func(value string, _ time.Time) (int, bool) {
+		p, ok := priorities[value]
+		if !ok {
+			return 0, true // drop values with no known priority
+		}
+		return p, false
+	}
+}
+
+func TestLazyPQueuePopRefreshesStaleHead(t *testing.T) {
+	priorities := map[string]int{"a": 10, "b": 5}
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(priorities))
+
+	pq.Push("a", 10)
+	pq.Push("b", 5)
+
+	// "a" drifts down after being pushed, so it should no longer be
+	// popped first even though it was cached with the highest priority.
+	priorities["a"] = 1
+
+	value, priority, ok := pq.Pop(time.Now())
+	if !ok {
+		t.Fatalf("Pop() returned ok=false, want an item")
+	}
+	if value != "b" || priority != 5 {
+		t.Fatalf("Pop() = (%q, %d), want (\"b\", 5)", value, priority)
+	}
+
+	value, priority, ok = pq.Pop(time.Now())
+	if !ok {
+		t.Fatalf("Pop() returned ok=false, want an item")
+	}
+	if value != "a" || priority != 1 {
+		t.Fatalf("Pop() = (%q, %d), want (\"a\", 1) (refreshed priority retained)", value, priority)
+	}
+}
+
+func TestLazyPQueuePeekDoesNotRemove(t *testing.T) {
+	priorities := map[string]int{"a": 10}
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(priorities))
+	pq.Push("a", 10)
+
+	value, priority, ok := pq.Peek(time.Now())
+	if !ok || value != "a" || priority != 10 {
+		t.Fatalf("Peek() = (%q, %d, %v), want (\"a\", 10, true)", value, priority, ok)
+	}
+	if got := pq.Size(); got != 1 {
+		t.Fatalf("Size() = %d, want 1 (Peek must not remove)", got)
+	}
+}
+
+func TestLazyPQueuePeriodicReheapifyRefreshesBuriedItems(t *testing.T) {
+	priorities := map[string]int{"a": 1, "b": 2, "c": 3}
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(priorities))
+	pq.SetReheapifyInterval(1)
+
+	pq.Push("a", 1)
+	pq.Push("b", 2)
+	pq.Push("c", 3)
+
+	// "a" is buried under "c" in the cached heap and would never be
+	// inspected by the lazy head-only refresh in Pop/Peek. Bump it above
+	// everything else and rely on the periodic reheapify to surface it.
+	priorities["a"] = 100
+
+	value, priority, ok := pq.Pop(time.Now())
+	if !ok {
+		t.Fatalf("Pop() returned ok=false, want an item")
+	}
+	if value != "a" || priority != 100 {
+		t.Fatalf("Pop() = (%q, %d), want (\"a\", 100); periodic reheapify should have surfaced it", value, priority)
+	}
+}
+
+func TestLazyPQueueRemoveAcrossPrimaryAndSecondary(t *testing.T) {
+	priorities := map[string]int{"a": 1, "b": 2, "c": 3}
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(priorities))
+
+	pq.Push("a", 1)
+	pq.Push("b", 2)
+
+	// Force "a" and "b" into the primary heap.
+	pq.Peek(time.Now())
+
+	// "c" stays in the secondary buffer, unmerged.
+	pq.Push("c", 3)
+
+	removed := pq.Remove(func(v string) bool {
+		return v == "b" || v == "c"
+	})
+	if removed != 2 {
+		t.Fatalf("Remove() = %d, want 2", removed)
+	}
+	if got := pq.Size(); got != 1 {
+		t.Fatalf("Size() = %d, want 1", got)
+	}
+
+	value, _, ok := pq.Pop(time.Now())
+	if !ok || value != "a" {
+		t.Fatalf("Pop() = (%q, %v), want (\"a\", true)", value, ok)
+	}
+}
+
+func TestLazyPQueueMultiPopNonPositiveN(t *testing.T) {
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(map[string]int{}))
+
+	values, priorities := pq.MultiPop(time.Now(), 0)
+	if values != nil || priorities != nil {
+		t.Fatalf("MultiPop(_, 0) = (%v, %v), want (nil, nil)", values, priorities)
+	}
+
+	values, priorities = pq.MultiPop(time.Now(), -5)
+	if values != nil || priorities != nil {
+		t.Fatalf("MultiPop(_, -5) = (%v, %v), want (nil, nil)", values, priorities)
+	}
+}
+
+func TestLazyPQueueMultiPopRunsDry(t *testing.T) {
+	priorities := map[string]int{"a": 1, "b": 2}
+	pq := NewMaxLazyPQueue[string, int](mapRefresh(priorities))
+	pq.Push("a", 1)
+	pq.Push("b", 2)
+
+	values, gotPriorities := pq.MultiPop(time.Now(), 5)
+	if len(values) != 2 {
+		t.Fatalf("MultiPop(_, 5) returned %d values, want 2 (queue should run dry)", len(values))
+	}
+	if values[0] != "b" || values[1] != "a" {
+		t.Fatalf("MultiPop(_, 5) values = %v, want [\"b\", \"a\"]", values)
+	}
+	if len(gotPriorities) != 2 {
+		t.Fatalf("MultiPop(_, 5) returned %d priorities, want 2", len(gotPriorities))
+	}
+	if !pq.Empty() {
+		t.Fatalf("expected queue to be empty after draining it")
+	}
+}
@@ -1,67 +1,74 @@
 package laney
 
 import (
+	"cmp"
 	"fmt"
 	"sync"
 )
 
-// PQType represents a priority queue ordering kind (see MAXPQ and MINPQ)
-type PQType int
+type item[V any, P cmp.Ordered] struct {
+	value    V
+	priority P
+}
 
-const (
-	MAXPQ PQType = iota
-	MINPQ
-)
+func newItem[V any, P cmp.Ordered](value V, priority P) *item[V, P] {
+	return &item[V, P]{
+		value:    value,
+		priority: priority,
+	}
+}
 
-type item[T any] struct {
-	value    T
-	priority int
+func (i *item[V, P]) String() string {
+	return fmt.Sprintf("<item value:%v priority:%v>", i.value, i.priority)
 }
 
-// PQueue is a heap priority queue data structure implementation.
-// It can be whether max or min ordered and it is synchronized
-// and is safe for concurrent operations.
-type PQueue[T any] struct {
+// PQueue is a heap priority queue data structure implementation. Priorities
+// are ordered using a user-supplied Less function, so any cmp.Ordered type
+// (integers, floats, strings) can be used without risking the overflow
+// footguns of a queue hard-coded to int priorities. Types that do not
+// satisfy cmp.Ordered, such as time.Time, are not accepted as priorities,
+// not even by NewPQueueFunc. It is synchronized and is safe for concurrent
+// operations.
+type PQueue[V any, P cmp.Ordered] struct {
 	sync.RWMutex
-	items      []*item[T]
+	items      []*item[V, P]
 	elemsCount int
-	comparator func(int, int) bool
+	less       func(P, P) bool
 }
 
-func newItem[T any](value T, priority int) *item[T] {
-	return &item[T]{
-		value:    value,
-		priority: priority,
-	}
+// NewMaxPQueue creates a new priority queue that pops the highest priority
+// item first.
+func NewMaxPQueue[V any, P cmp.Ordered]() *PQueue[V, P] {
+	return NewPQueueFunc[V, P](func(a, b P) bool {
+		return a < b
+	})
 }
 
-func (i *item[T]) String() string {
-	return fmt.Sprintf("<item value:%v priority:%d>", i.value, i.priority)
+// NewMinPQueue creates a new priority queue that pops the lowest priority
+// item first.
+func NewMinPQueue[V any, P cmp.Ordered]() *PQueue[V, P] {
+	return NewPQueueFunc[V, P](func(a, b P) bool {
+		return a > b
+	})
 }
 
-// NewPQueue creates a new priority queue with the provided pqtype
-// ordering type
-func NewPQueue[T any](pqType PQType) *PQueue[T] {
-	var cmp func(int, int) bool
-
-	if pqType == MAXPQ {
-		cmp = max
-	} else {
-		cmp = min
-	}
-
-	items := make([]*item[T], 1)
+// NewPQueueFunc creates a new priority queue ordered by the provided less
+// function. less(a, b) should report whether priority a is lower ranked
+// than priority b, i.e. whichever priority is never less than any other
+// ends up at the head of the queue.
+func NewPQueueFunc[V any, P cmp.Ordered](less func(P, P) bool) *PQueue[V, P] {
+	items := make([]*item[V, P], 1)
 	items[0] = nil // Heap queue first element should always be nil
 
-	return &PQueue[T]{
+	return &PQueue[V, P]{
 		items:      items,
 		elemsCount: 0,
-		comparator: cmp,
+		less:       less,
 	}
 }
 
 // Push the value item into the priority queue with provided priority.
-func (pq *PQueue[T]) Push(value T, priority int) {
+func (pq *PQueue[V, P]) Push(value V, priority P) {
 	item := newItem(value, priority)
 
 	pq.Lock()
@@ -71,98 +78,143 @@ func (pq *PQueue[T]) Push(value T, priority int) {
 	pq.Unlock()
 }
 
-// Pop and returns the highest/lowest priority item (depending on whether
-// you're using a MINPQ or MAXPQ) from the priority queue
-func (pq *PQueue[T]) Pop() (T, int) {
+// Pop removes and returns the head item from the priority queue (depending
+// on whether you're using a min or max ordered queue), along with its
+// priority. The returned bool is false if the queue was empty.
+func (pq *PQueue[V, P]) Pop() (V, P, bool) {
 	pq.Lock()
 	defer pq.Unlock()
 
 	if pq.size() < 1 {
-		var nothing T
-		return nothing, 0
+		var value V
+		var priority P
+		return value, priority, false
 	}
 
-	var max *item[T] = pq.items[1]
+	var head *item[V, P] = pq.items[1]
 
 	pq.exch(1, pq.size())
 	pq.items = pq.items[0:pq.size()]
 	pq.elemsCount -= 1
 	pq.sink(1)
 
-	return max.value, max.priority
+	return head.value, head.priority, true
 }
 
-// Head returns the highest/lowest priority item (depending on whether
-// you're using a MINPQ or MAXPQ) from the priority queue
-func (pq *PQueue[T]) Head() (T, int) {
+// Head returns the head item from the priority queue (depending on whether
+// you're using a min or max ordered queue), along with its priority,
+// without removing it. The returned bool is false if the queue was empty.
+func (pq *PQueue[V, P]) Head() (V, P, bool) {
 	pq.RLock()
 	defer pq.RUnlock()
 
 	if pq.size() < 1 {
-		var nothing T
-		return nothing, 0
+		var value V
+		var priority P
+		return value, priority, false
 	}
 
-	headValue := pq.items[1].value
-	headPriority := pq.items[1].priority
+	headItem := pq.items[1]
 
-	return headValue, headPriority
+	return headItem.value, headItem.priority, true
 }
 
 // Size returns the elements present in the priority queue count
-func (pq *PQueue[T]) Size() int {
+func (pq *PQueue[V, P]) Size() int {
 	pq.RLock()
 	defer pq.RUnlock()
 	return pq.size()
 }
 
 // Check queue is empty
-func (pq *PQueue[T]) Empty() bool {
+func (pq *PQueue[V, P]) Empty() bool {
 	pq.RLock()
 	defer pq.RUnlock()
 	return pq.size() == 0
 }
 
-func (pq *PQueue[T]) size() int {
-	return pq.elemsCount
+// Snapshot returns a consistent copy of the queue's values in priority
+// order (head first), taken under the read lock, without draining the
+// queue.
+func (pq *PQueue[V, P]) Snapshot() []V {
+	pq.RLock()
+	scratch := pq.cloneLocked()
+	pq.RUnlock()
+
+	out := make([]V, 0, scratch.elemsCount)
+	for {
+		v, _, ok := scratch.Pop()
+		if !ok {
+			break
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Clear empties the priority queue.
+func (pq *PQueue[V, P]) Clear() {
+	pq.Lock()
+	defer pq.Unlock()
+
+	pq.items = pq.items[:1]
+	pq.elemsCount = 0
+}
+
+// Clone returns a new PQueue with an independent copy of the same
+// contents and ordering.
+func (pq *PQueue[V, P]) Clone() *PQueue[V, P] {
+	pq.RLock()
+	defer pq.RUnlock()
+
+	return pq.cloneLocked()
 }
 
-func max(i, j int) bool {
-	return i < j
+// cloneLocked returns an independent copy of pq, assuming the caller
+// already holds at least the read lock.
+func (pq *PQueue[V, P]) cloneLocked() *PQueue[V, P] {
+	items := make([]*item[V, P], len(pq.items))
+	copy(items, pq.items)
+
+	return &PQueue[V, P]{
+		items:      items,
+		elemsCount: pq.elemsCount,
+		less:       pq.less,
+	}
 }
 
-func min(i, j int) bool {
-	return i > j
+func (pq *PQueue[V, P]) size() int {
+	return pq.elemsCount
 }
 
-func (pq *PQueue[T]) less(i, j int) bool {
-	return pq.comparator(pq.items[i].priority, pq.items[j].priority)
+func (pq *PQueue[V, P]) compare(i, j int) bool {
+	return pq.less(pq.items[i].priority, pq.items[j].priority)
 }
 
-func (pq *PQueue[T]) exch(i, j int) {
-	var tmpItem *item[T] = pq.items[i]
+func (pq *PQueue[V, P]) exch(i, j int) {
+	var tmpItem *item[V, P] = pq.items[i]
 
 	pq.items[i] = pq.items[j]
 	pq.items[j] = tmpItem
 }
 
-func (pq *PQueue[T]) swim(k int) {
-	for k > 1 && pq.less(k/2, k) {
+func (pq *PQueue[V, P]) swim(k int) {
+	for k > 1 && pq.compare(k/2, k) {
 		pq.exch(k/2, k)
 		k = k / 2
 	}
 
 }
 
-func (pq *PQueue[T]) sink(k int) {
+func (pq *PQueue[V, P]) sink(k int) {
 	for 2*k <= pq.size() {
 		var j int = 2 * k
 
-		if j < pq.size() && pq.less(j, j+1) {
+		if j < pq.size() && pq.compare(j, j+1) {
 			j++
 		}
 
-		if !pq.less(k, j) {
+		if !pq.compare(k, j) {
 			break
 		}
 
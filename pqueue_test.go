@@ -0,0 +1,100 @@
+package laney
+
+import "testing"
+
+func TestPQueueMaxOrdering(t *testing.T) {
+	pq := NewMaxPQueue[string, int]()
+
+	pq.Push("low", 1)
+	pq.Push("high", 10)
+	pq.Push("mid", 5)
+
+	wantOrder := []string{"high", "mid", "low"}
+	for _, want := range wantOrder {
+		value, _, ok := pq.Pop()
+		if !ok {
+			t.Fatalf("Pop() returned ok=false, want an item")
+		}
+		if value != want {
+			t.Fatalf("Pop() = %q, want %q", value, want)
+		}
+	}
+}
+
+func TestPQueueMinOrdering(t *testing.T) {
+	pq := NewMinPQueue[string, int]()
+
+	pq.Push("high", 10)
+	pq.Push("low", 1)
+	pq.Push("mid", 5)
+
+	wantOrder := []string{"low", "mid", "high"}
+	for _, want := range wantOrder {
+		value, _, ok := pq.Pop()
+		if !ok {
+			t.Fatalf("Pop() returned ok=false, want an item")
+		}
+		if value != want {
+			t.Fatalf("Pop() = %q, want %q", value, want)
+		}
+	}
+}
+
+func TestPQueueFuncCustomOrdering(t *testing.T) {
+	// Orders by the absolute distance from 0, smallest distance first.
+	less := func(a, b int) bool {
+		abs := func(n int) int {
+			if n < 0 {
+				return -n
+			}
+			return n
+		}
+		return abs(a) > abs(b)
+	}
+
+	pq := NewPQueueFunc[string, int](less)
+	pq.Push("far", -10)
+	pq.Push("near", 2)
+	pq.Push("mid", -5)
+
+	wantOrder := []string{"near", "mid", "far"}
+	for _, want := range wantOrder {
+		value, _, ok := pq.Pop()
+		if !ok {
+			t.Fatalf("Pop() returned ok=false, want an item")
+		}
+		if value != want {
+			t.Fatalf("Pop() = %q, want %q", value, want)
+		}
+	}
+}
+
+func TestPQueuePopEmpty(t *testing.T) {
+	pq := NewMaxPQueue[string, int]()
+
+	if _, _, ok := pq.Pop(); ok {
+		t.Fatalf("Pop() on empty queue returned ok=true")
+	}
+	if _, _, ok := pq.Head(); ok {
+		t.Fatalf("Head() on empty queue returned ok=true")
+	}
+	if !pq.Empty() {
+		t.Fatalf("expected Empty() to be true")
+	}
+}
+
+func TestPQueueCloneIsIndependent(t *testing.T) {
+	pq := NewMaxPQueue[string, int]()
+	pq.Push("a", 1)
+	pq.Push("b", 2)
+
+	clone := pq.Clone()
+	clone.Push("c", 3)
+
+	if got := pq.Size(); got != 2 {
+		t.Fatalf("original Size() = %d, want 2 (unaffected by clone mutation)", got)
+	}
+	if got := clone.Size(); got != 3 {
+		t.Fatalf("clone Size() = %d, want 3", got)
+	}
+}
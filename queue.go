@@ -13,8 +13,13 @@ type Queue[T any] struct {
 }
 
 func NewQueue[T any]() *Queue[T] {
+	return NewCappedQueue[T](-1)
+}
+
+// NewCappedQueue creates a Queue with the specified capacity limit.
+func NewCappedQueue[T any](capacity int) *Queue[T] {
 	return &Queue[T]{
-		Deque: NewDeque[T](),
+		Deque: NewCappedDeque[T](capacity),
 	}
 }
 